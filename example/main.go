@@ -28,12 +28,10 @@ func main() {
 	// Setup router with observability middleware
 	r := chi.NewRouter()
 
-	// Middleware order matters!
-	r.Use(middleware.RequestID)            // Generate request ID
-	r.Use(obs.TraceMiddleware())           // Propagate trace context (X-Request-ID, X-Trace-ID)
-	r.Use(stack.MetricsMiddleware())       // Collect Prometheus metrics
-	r.Use(stack.LoggingMiddleware())       // Log requests with trace context
-	r.Use(middleware.Recoverer)            // Recover from panics
+	// Pipeline applies request ID assignment, trace propagation, metrics,
+	// logging, and panic recovery in the one order they all need to run in,
+	// skipping metrics/logging for the health and metrics endpoints below.
+	r.Use(stack.Pipeline(obs.Skip("/healthz", "/readyz", "/metrics")).Middleware())
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	// Health endpoints
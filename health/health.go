@@ -5,77 +5,281 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Assistencia-Familiar-Francana/go-observability/metrics"
 )
 
 // Checker defines a health check function.
 type Checker func(ctx context.Context) error
 
-// CheckResult represents the result of a health check.
+// Kind classifies a check by which Kubernetes probe consumes it.
+type Kind string
+
+const (
+	Liveness  Kind = "liveness"
+	Readiness Kind = "readiness"
+	Startup   Kind = "startup"
+)
+
+// defaultTimeout is used for checks registered without an explicit Timeout,
+// matching the previous shared deadline.
+const defaultTimeout = 5 * time.Second
+
+// Check describes a single dependency check and how to run it.
+type Check struct {
+	// Name identifies the check in responses and metrics.
+	Name string
+	// Kind selects which handler(s) run this check.
+	Kind Kind
+	// Timeout bounds a single run of Check. Defaults to 5s.
+	Timeout time.Duration
+	// CacheTTL, when positive, reuses the last successful result instead of
+	// re-running the check for that long. Useful for checks hit by
+	// frequent Kubernetes probes against systems that shouldn't be
+	// hammered every second.
+	CacheTTL time.Duration
+	// Critical marks whether a failure makes readiness report 503 ("error")
+	// rather than 200 with a "degraded" status.
+	Critical bool
+	// Check is the function that performs the dependency check.
+	Check Checker
+}
+
+// CheckResult represents the result of a single health check.
 type CheckResult struct {
 	Name   string `json:"name"`
-	Status string `json:"status"` // "ok" or "error"
+	Status string `json:"status"` // "ok", "degraded", or "error"
 	Error  string `json:"error,omitempty"`
 }
 
-// HealthResponse represents the full health check response.
+// HealthResponse represents a full health check response.
 type HealthResponse struct {
-	Status string        `json:"status"` // "ok" or "error"
+	Status string        `json:"status"` // "ok", "degraded", or "error"
 	Checks []CheckResult `json:"checks,omitempty"`
 }
 
-// LivenessHandler returns a simple liveness probe handler.
-// This should always return 200 OK if the process is running.
-func LivenessHandler() http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+type cacheEntry struct {
+	result    CheckResult
+	expiresAt time.Time
+}
+
+// Registry holds registered checks, runs them concurrently with per-check
+// timeouts and TTL caching, and reports results through the given metrics
+// collector.
+type Registry struct {
+	collector *metrics.Collector
+
+	mu     sync.Mutex
+	checks []Check
+	cache  map[string]cacheEntry
+
+	startupMu   sync.Mutex
+	startupDone map[string]bool
+}
+
+// NewRegistry creates an empty Registry. collector may be nil, in which case
+// per-check metrics are skipped.
+func NewRegistry(collector *metrics.Collector) *Registry {
+	return &Registry{
+		collector:   collector,
+		cache:       make(map[string]cacheEntry),
+		startupDone: make(map[string]bool),
 	}
 }
 
-// ReadinessHandler returns a readiness probe handler that checks dependencies.
-// Returns 200 OK only if all checks pass, 503 Service Unavailable otherwise.
-func ReadinessHandler(checkers ...Checker) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
-		defer cancel()
+// Register adds a check to the registry. Checks with a zero Timeout use
+// defaultTimeout.
+func (r *Registry) Register(c Check) {
+	if c.Timeout <= 0 {
+		c.Timeout = defaultTimeout
+	}
 
-		results := make([]CheckResult, 0, len(checkers))
-		allOK := true
+	r.mu.Lock()
+	r.checks = append(r.checks, c)
+	r.mu.Unlock()
+}
 
-		for _, checker := range checkers {
-			// Extract checker name from context if available
-			name := "dependency"
-			if nameCtx, ok := checker.(interface{ Name() string }); ok {
-				name = nameCtx.Name()
-			}
+// checksOf returns the registered checks matching kind.
+func (r *Registry) checksOf(kind Kind) []Check {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-			result := CheckResult{Name: name, Status: "ok"}
-			if err := checker(ctx); err != nil {
-				result.Status = "error"
-				result.Error = err.Error()
-				allOK = false
+	matched := make([]Check, 0, len(r.checks))
+	for _, c := range r.checks {
+		if c.Kind == kind {
+			matched = append(matched, c)
+		}
+	}
+	return matched
+}
+
+// run executes c, honoring its cache TTL, and records metrics.
+func (r *Registry) run(ctx context.Context, c Check) CheckResult {
+	if cached, ok := r.cached(c.Name); ok {
+		return cached
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(checkCtx)
+	duration := time.Since(start)
+
+	result := CheckResult{Name: c.Name, Status: "ok"}
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	}
+
+	if r.collector != nil {
+		r.collector.ObserveHealthCheck(c.Name, err == nil, duration)
+	}
+
+	if err == nil && c.CacheTTL > 0 {
+		r.mu.Lock()
+		r.cache[c.Name] = cacheEntry{result: result, expiresAt: time.Now().Add(c.CacheTTL)}
+		r.mu.Unlock()
+	}
+
+	if err == nil && c.Kind == Startup {
+		r.startupMu.Lock()
+		r.startupDone[c.Name] = true
+		r.startupMu.Unlock()
+	}
+
+	return result
+}
+
+func (r *Registry) cached(name string) (CheckResult, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.cache[name]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return CheckResult{}, false
+	}
+	return entry.result, true
+}
+
+// ReadinessHandler runs all Readiness checks concurrently and reports 200
+// ("ok", or "degraded" if only non-critical checks failed) or 503 ("error",
+// if any critical check failed).
+func (r *Registry) ReadinessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		checks := r.checksOf(Readiness)
+		results := make([]CheckResult, len(checks))
+
+		g, ctx := errgroup.WithContext(req.Context())
+		for i, c := range checks {
+			i, c := i, c
+			g.Go(func() error {
+				results[i] = r.run(ctx, c)
+				return nil
+			})
+		}
+		_ = g.Wait() // individual check failures are captured in results, not returned
+
+		status := http.StatusOK
+		overall := "ok"
+		for i, result := range results {
+			if result.Status != "error" {
+				continue
+			}
+			if checks[i].Critical {
+				overall = "error"
+				status = http.StatusServiceUnavailable
+			} else {
+				results[i].Status = "degraded"
+				if overall == "ok" {
+					overall = "degraded"
+				}
 			}
-			results = append(results, result)
 		}
 
-		response := HealthResponse{
-			Status: "ok",
-			Checks: results,
+		writeJSON(w, status, HealthResponse{Status: overall, Checks: results})
+	}
+}
+
+// StartupHandler reports 200 once every registered Startup check has passed
+// at least once, matching Kubernetes' startupProbe semantics: after that,
+// kubelet stops calling it and switches to liveness/readiness. Until then it
+// reports 503 so kubelet keeps waiting instead of killing the container.
+func (r *Registry) StartupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		checks := r.checksOf(Startup)
+		results := make([]CheckResult, len(checks))
+
+		g, ctx := errgroup.WithContext(req.Context())
+		for i, c := range checks {
+			i, c := i, c
+			g.Go(func() error {
+				r.startupMu.Lock()
+				done := r.startupDone[c.Name]
+				r.startupMu.Unlock()
+
+				if done {
+					results[i] = CheckResult{Name: c.Name, Status: "ok"}
+					return nil
+				}
+				results[i] = r.run(ctx, c)
+				return nil
+			})
 		}
+		_ = g.Wait()
 
-		statusCode := http.StatusOK
-		if !allOK {
-			response.Status = "error"
-			statusCode = http.StatusServiceUnavailable
+		status := http.StatusOK
+		overall := "ok"
+		for _, result := range results {
+			if result.Status != "ok" {
+				overall = "error"
+				status = http.StatusServiceUnavailable
+				break
+			}
 		}
 
+		writeJSON(w, status, HealthResponse{Status: overall, Checks: results})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp HealthResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// LivenessHandler returns a simple liveness probe handler.
+// This should always return 200 OK if the process is running.
+func LivenessHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(statusCode)
-		json.NewEncoder(w).Encode(response)
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	}
+}
+
+// ReadinessHandler returns a readiness probe handler that checks dependencies.
+// Returns 200 OK only if all checks pass, 503 Service Unavailable otherwise.
+// It wraps checkers into a one-off Registry so checks still run
+// concurrently, but without caching or per-check timeouts beyond the shared
+// 5s default; prefer building a Registry directly to configure those.
+//
+// Checker is a plain func type, so a checker built by DatabaseChecker,
+// RedisChecker, etc. carries no name by the time it reaches here; each is
+// registered as "dependency-N" rather than its constructor's name.
+func ReadinessHandler(checkers ...Checker) http.HandlerFunc {
+	registry := NewRegistry(nil)
+	for i, checker := range checkers {
+		name := fmt.Sprintf("dependency-%d", i)
+		registry.Register(Check{Name: name, Kind: Readiness, Critical: true, Check: checker})
 	}
+	return registry.ReadinessHandler()
 }
 
 // namedChecker wraps a checker with a name.
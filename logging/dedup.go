@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// NewDedupingWriter wraps out so that lines whose dedupKey matches the
+// immediately preceding one are suppressed while they keep repeating within
+// window; the next line with a different key (or the same key once window
+// has elapsed) is preceded by one annotated copy of the suppressed line
+// recording how many were dropped, via a "repeated":N field. The key
+// ignores each line's timestamp (zerolog's and slog's default "time"
+// field), since this module's loggers always set one -- comparing full
+// lines would never match two real log events and the suppression would
+// never fire. Pass the result as the output of a JSON handler/writer to
+// keep high-volume, repetitive logging (e.g. a retry loop logging the same
+// error) from flooding log aggregators; lines that vary in any field
+// besides their timestamp are never deduped.
+func NewDedupingWriter(out io.Writer, window time.Duration) io.Writer {
+	return &dedupWriter{out: out, window: window}
+}
+
+type dedupWriter struct {
+	mu       sync.Mutex
+	out      io.Writer
+	window   time.Duration
+	lastLine []byte
+	lastKey  string
+	lastAt   time.Time
+	repeated int
+}
+
+func (d *dedupWriter) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	key := dedupKey(p)
+	if d.lastLine != nil && key == d.lastKey && now.Sub(d.lastAt) < d.window {
+		d.repeated++
+		d.lastAt = now
+		return len(p), nil
+	}
+
+	if d.repeated > 0 {
+		if _, err := d.out.Write(annotateRepeated(d.lastLine, d.repeated)); err != nil {
+			return 0, err
+		}
+		d.repeated = 0
+	}
+
+	if _, err := d.out.Write(p); err != nil {
+		return 0, err
+	}
+
+	d.lastLine = append(d.lastLine[:0], p...)
+	d.lastKey = key
+	d.lastAt = now
+	return len(p), nil
+}
+
+// dedupKey returns the part of a rendered log line that two occurrences of
+// "the same" log event are expected to share. For JSON lines (zerolog and
+// slog's JSON handler) it re-marshals the line with its "time"/"timestamp"
+// field removed, which also normalizes key order. For non-JSON lines (e.g.
+// slog's pretty text handler) it strips any "time=..." token instead.
+func dedupKey(line []byte) string {
+	trimmed := bytes.TrimRight(line, "\n")
+
+	var fields map[string]any
+	if err := json.Unmarshal(trimmed, &fields); err == nil {
+		delete(fields, "time")
+		delete(fields, "timestamp")
+		if key, err := json.Marshal(fields); err == nil {
+			return string(key)
+		}
+	}
+
+	return string(stripTimeToken(trimmed))
+}
+
+// stripTimeToken drops a whitespace-delimited "time=..." token, as written
+// by slog's text handler, from line.
+func stripTimeToken(line []byte) []byte {
+	fields := bytes.Fields(line)
+	out := fields[:0]
+	for _, f := range fields {
+		if bytes.HasPrefix(f, []byte("time=")) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return bytes.Join(out, []byte(" "))
+}
+
+// annotateRepeated inserts a "repeated":n field into line, a JSON log
+// line, just before its closing brace. Lines that aren't JSON objects
+// (e.g. the pretty text handler's output) get a trailing "repeated=n"
+// instead. It returns a new slice and never writes into line's backing
+// array.
+func annotateRepeated(line []byte, n int) []byte {
+	trimmed := bytes.TrimRight(line, "\n")
+	if bytes.HasSuffix(trimmed, []byte("}")) {
+		out := make([]byte, 0, len(trimmed)+16)
+		out = append(out, trimmed[:len(trimmed)-1]...)
+		out = append(out, []byte(fmt.Sprintf(`,"repeated":%d}`, n))...)
+		return append(out, '\n')
+	}
+
+	out := make([]byte, 0, len(trimmed)+16)
+	out = append(out, trimmed...)
+	out = append(out, []byte(fmt.Sprintf(" repeated=%d", n))...)
+	return append(out, '\n')
+}
@@ -1,127 +1,158 @@
-// Package logging provides structured logging with zerolog.
+// Package logging provides a pluggable structured logging abstraction, with
+// zerolog and log/slog backends.
 package logging
 
 import (
 	"context"
 	"net/http"
-	"os"
 	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/Assistencia-Familiar-Francana/go-observability/trace"
 )
 
 type contextKey string
 
 const loggerKey contextKey = "logger"
 
-// Logger wraps zerolog.Logger with service context.
-type Logger struct {
-	zerolog.Logger
-	serviceName string
+// Field is a single structured logging key/value pair, used with Logger.With.
+type Field struct {
+	Key   string
+	Value any
 }
 
-// NewLogger creates a new structured logger for the given service.
-func NewLogger(serviceName string, debug bool) *Logger {
-	zerolog.TimeFieldFormat = time.RFC3339
+// Str builds a string Field.
+func Str(key, value string) Field { return Field{Key: key, Value: value} }
 
-	level := zerolog.InfoLevel
-	if debug {
-		level = zerolog.DebugLevel
-	}
+// Int builds an int Field.
+func Int(key string, value int) Field { return Field{Key: key, Value: value} }
 
-	logger := zerolog.New(os.Stdout).
-		Level(level).
-		With().
-		Timestamp().
-		Str("service", serviceName).
-		Logger()
+// Int64 builds an int64 Field.
+func Int64(key string, value int64) Field { return Field{Key: key, Value: value} }
 
-	return &Logger{
-		Logger:      logger,
-		serviceName: serviceName,
-	}
+// Err builds an "error" Field from err.
+func Err(err error) Field { return Field{Key: "error", Value: err} }
+
+// Event is a single in-flight log entry, built up with the field setters
+// below and emitted by Msg or Msgf.
+type Event interface {
+	Str(key, value string) Event
+	Int(key string, value int) Event
+	Int64(key string, value int64) Event
+	Err(err error) Event
+	Msg(msg string)
+	Msgf(format string, args ...any)
 }
 
-// WithContext returns a new logger with values from the context added.
-func (l *Logger) WithContext(ctx context.Context) zerolog.Logger {
-	logger := l.Logger
+// Logger is the structured logging interface every backend implements.
+// Consumers that only depend on Logger work identically regardless of
+// which LoggerBackend produced it.
+type Logger interface {
+	Debug() Event
+	Info() Event
+	Warn() Event
+	Error() Event
+	Fatal() Event
+
+	// With returns a new Logger with fields attached to every subsequent event.
+	With(fields ...Field) Logger
+	// WithContext returns a new Logger enriched with trace_id/span_id/
+	// request_id (and user_id, if set) from ctx.
+	WithContext(ctx context.Context) Logger
+	// Middleware returns chi-compatible middleware that logs each request.
+	Middleware(next http.Handler) http.Handler
+}
 
-	// Add trace_id if present
-	if traceID := ctx.Value("trace_id"); traceID != nil {
-		if id, ok := traceID.(string); ok {
-			logger = logger.With().Str("trace_id", id).Logger()
-		}
+// LoggerBackend constructs a Logger for serviceName. BackendZerolog and
+// BackendSlog are the built-in backends; pass either to NewLogger/NewStack,
+// or supply your own factory to plug in a different backend entirely.
+type LoggerBackend func(serviceName string, debug bool) Logger
+
+// NewLogger creates a new structured logger for the given service. backend
+// is optional and defaults to BackendZerolog, preserving prior behavior.
+func NewLogger(serviceName string, debug bool, backend ...LoggerBackend) Logger {
+	b := BackendZerolog
+	if len(backend) > 0 && backend[0] != nil {
+		b = backend[0]
 	}
+	return b(serviceName, debug)
+}
 
-	// Add request_id if present
-	if requestID := ctx.Value("request_id"); requestID != nil {
-		if id, ok := requestID.(string); ok {
-			logger = logger.With().Str("request_id", id).Logger()
-		}
-	}
+// requestContextFields extracts the fields WithContext implementations
+// should attach from ctx.
+func requestContextFields(ctx context.Context) []Field {
+	var fields []Field
 
-	// Add user_id if present
-	if userID := ctx.Value("user_id"); userID != nil {
-		if id, ok := userID.(string); ok {
-			logger = logger.With().Str("user_id", id).Logger()
-		}
+	if traceID := trace.TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, Str("trace_id", traceID))
+	}
+	if spanID := trace.SpanIDFromContext(ctx); spanID != "" {
+		fields = append(fields, Str("span_id", spanID))
 	}
+	if requestID := trace.RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, Str("request_id", requestID))
+	}
+	if userID, ok := ctx.Value("user_id").(string); ok && userID != "" {
+		fields = append(fields, Str("user_id", userID))
+	}
+
+	return fields
+}
 
-	return logger
+// withRequestContext is the shared WithContext implementation: it only
+// needs Logger.With, so every backend can delegate to it.
+func withRequestContext(l Logger, ctx context.Context) Logger {
+	fields := requestContextFields(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
 }
 
-// Middleware returns chi-compatible middleware for request logging.
-func (l *Logger) Middleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		start := time.Now()
-
-		// Create logger with request context
-		logger := l.WithContext(r.Context())
-
-		// Log incoming request
-		logger.Debug().
-			Str("http.method", r.Method).
-			Str("http.path", r.URL.Path).
-			Str("http.remote_addr", r.RemoteAddr).
-			Msg("request started")
-
-		// Wrap response writer to capture status
-		ww := &responseWriter{ResponseWriter: w}
-
-		// Add logger to request context
-		ctx := context.WithValue(r.Context(), loggerKey, &logger)
-
-		// Process request
-		next.ServeHTTP(ww, r.WithContext(ctx))
-
-		// Log completed request
-		duration := time.Since(start)
-		logEvent := logger.Info()
-
-		// Use Error level for 5xx status codes
-		if ww.statusCode >= 500 {
-			logEvent = logger.Error()
-		} else if ww.statusCode >= 400 {
-			logEvent = logger.Warn()
-		}
-
-		logEvent.
-			Str("http.method", r.Method).
-			Str("http.path", r.URL.Path).
-			Int("http.status", ww.statusCode).
-			Int64("duration_ms", duration.Milliseconds()).
-			Msg("request completed")
-	})
+// middleware is the shared Middleware implementation: it only needs the
+// Logger interface, so every backend can delegate to it.
+func middleware(l Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			reqLogger := l.WithContext(r.Context())
+
+			reqLogger.Debug().
+				Str("http.method", r.Method).
+				Str("http.path", r.URL.Path).
+				Str("http.remote_addr", r.RemoteAddr).
+				Msg("request started")
+
+			ww := &responseWriter{ResponseWriter: w}
+			ctx := context.WithValue(r.Context(), loggerKey, reqLogger)
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			duration := time.Since(start)
+			event := reqLogger.Info()
+			if ww.statusCode >= 500 {
+				event = reqLogger.Error()
+			} else if ww.statusCode >= 400 {
+				event = reqLogger.Warn()
+			}
+
+			event.
+				Str("http.method", r.Method).
+				Str("http.path", r.URL.Path).
+				Int("http.status", ww.statusCode).
+				Int64("duration_ms", duration.Milliseconds()).
+				Msg("request completed")
+		})
+	}
 }
 
-// FromContext extracts the logger from the request context.
-func FromContext(ctx context.Context) *zerolog.Logger {
-	if logger, ok := ctx.Value(loggerKey).(*zerolog.Logger); ok {
+// FromContext extracts the logger set by Logger.Middleware from ctx,
+// falling back to a default BackendZerolog logger if none was set.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(loggerKey).(Logger); ok {
 		return logger
 	}
-	// Return a default logger if not found
-	defaultLogger := zerolog.New(os.Stdout).With().Timestamp().Logger()
-	return &defaultLogger
+	return BackendZerolog("", false)
 }
 
 // responseWriter wraps http.ResponseWriter to capture the status code.
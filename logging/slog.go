@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+)
+
+// levelFatal is logged at a level above slog.LevelError; Event.Msg exits the
+// process after logging, matching zerolog's Fatal() behavior.
+const levelFatal = slog.LevelError + 4
+
+// BackendSlog builds a Logger backed by log/slog with a JSON handler,
+// suitable for production log aggregation.
+var BackendSlog LoggerBackend = newSlogLogger
+
+// BackendSlogPretty builds a Logger backed by log/slog with a human-readable
+// text handler, for local development.
+var BackendSlogPretty LoggerBackend = newSlogPrettyLogger
+
+func newSlogLogger(serviceName string, debug bool) Logger {
+	opts := &slog.HandlerOptions{Level: slogLevel(debug)}
+	return newSlogLoggerWithHandler(serviceName, slog.NewJSONHandler(os.Stdout, opts))
+}
+
+func newSlogPrettyLogger(serviceName string, debug bool) Logger {
+	opts := &slog.HandlerOptions{Level: slogLevel(debug)}
+	return newSlogLoggerWithHandler(serviceName, slog.NewTextHandler(os.Stdout, opts))
+}
+
+func slogLevel(debug bool) slog.Level {
+	if debug {
+		return slog.LevelDebug
+	}
+	return slog.LevelInfo
+}
+
+func newSlogLoggerWithHandler(serviceName string, handler slog.Handler) Logger {
+	logger := slog.New(handler).With("service", serviceName)
+	return &slogLogger{logger: logger}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l *slogLogger) Debug() Event { return &slogEvent{logger: l.logger, level: slog.LevelDebug} }
+func (l *slogLogger) Info() Event  { return &slogEvent{logger: l.logger, level: slog.LevelInfo} }
+func (l *slogLogger) Warn() Event  { return &slogEvent{logger: l.logger, level: slog.LevelWarn} }
+func (l *slogLogger) Error() Event { return &slogEvent{logger: l.logger, level: slog.LevelError} }
+func (l *slogLogger) Fatal() Event { return &slogEvent{logger: l.logger, level: levelFatal} }
+
+func (l *slogLogger) With(fields ...Field) Logger {
+	args := make([]any, 0, len(fields)*2)
+	for _, f := range fields {
+		args = append(args, f.Key, f.Value)
+	}
+	return &slogLogger{logger: l.logger.With(args...)}
+}
+
+func (l *slogLogger) WithContext(ctx context.Context) Logger {
+	return withRequestContext(l, ctx)
+}
+
+func (l *slogLogger) Middleware(next http.Handler) http.Handler {
+	return middleware(l)(next)
+}
+
+// slogEvent adapts *slog.Logger to the Event interface, accumulating
+// attributes until Msg/Msgf emits them in a single Log call.
+type slogEvent struct {
+	logger *slog.Logger
+	level  slog.Level
+	attrs  []any
+}
+
+func (e *slogEvent) Str(key, value string) Event {
+	e.attrs = append(e.attrs, key, value)
+	return e
+}
+
+func (e *slogEvent) Int(key string, value int) Event {
+	e.attrs = append(e.attrs, key, value)
+	return e
+}
+
+func (e *slogEvent) Int64(key string, value int64) Event {
+	e.attrs = append(e.attrs, key, value)
+	return e
+}
+
+func (e *slogEvent) Err(err error) Event {
+	e.attrs = append(e.attrs, "error", err)
+	return e
+}
+
+func (e *slogEvent) Msg(msg string) {
+	e.logger.Log(context.Background(), e.level, msg, e.attrs...)
+	if e.level == levelFatal {
+		os.Exit(1)
+	}
+}
+
+func (e *slogEvent) Msgf(format string, args ...any) {
+	e.Msg(fmt.Sprintf(format, args...))
+}
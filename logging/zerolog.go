@@ -0,0 +1,95 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// BackendZerolog builds a Logger backed by zerolog, the original
+// implementation of this package.
+var BackendZerolog LoggerBackend = newZerologLogger
+
+type zerologLogger struct {
+	logger zerolog.Logger
+}
+
+func newZerologLogger(serviceName string, debug bool) Logger {
+	zerolog.TimeFieldFormat = time.RFC3339
+
+	level := zerolog.InfoLevel
+	if debug {
+		level = zerolog.DebugLevel
+	}
+
+	logger := zerolog.New(os.Stdout).
+		Level(level).
+		With().
+		Timestamp().
+		Str("service", serviceName).
+		Logger()
+
+	return &zerologLogger{logger: logger}
+}
+
+func (l *zerologLogger) Debug() Event { return &zerologEvent{event: l.logger.Debug()} }
+func (l *zerologLogger) Info() Event  { return &zerologEvent{event: l.logger.Info()} }
+func (l *zerologLogger) Warn() Event  { return &zerologEvent{event: l.logger.Warn()} }
+func (l *zerologLogger) Error() Event { return &zerologEvent{event: l.logger.Error()} }
+func (l *zerologLogger) Fatal() Event { return &zerologEvent{event: l.logger.Fatal()} }
+
+func (l *zerologLogger) With(fields ...Field) Logger {
+	ctx := l.logger.With()
+	for _, f := range fields {
+		if err, ok := f.Value.(error); ok {
+			ctx = ctx.AnErr(f.Key, err)
+			continue
+		}
+		ctx = ctx.Interface(f.Key, f.Value)
+	}
+	return &zerologLogger{logger: ctx.Logger()}
+}
+
+func (l *zerologLogger) WithContext(ctx context.Context) Logger {
+	return withRequestContext(l, ctx)
+}
+
+func (l *zerologLogger) Middleware(next http.Handler) http.Handler {
+	return middleware(l)(next)
+}
+
+// zerologEvent adapts *zerolog.Event to the Event interface.
+type zerologEvent struct {
+	event *zerolog.Event
+}
+
+func (e *zerologEvent) Str(key, value string) Event {
+	e.event = e.event.Str(key, value)
+	return e
+}
+
+func (e *zerologEvent) Int(key string, value int) Event {
+	e.event = e.event.Int(key, value)
+	return e
+}
+
+func (e *zerologEvent) Int64(key string, value int64) Event {
+	e.event = e.event.Int64(key, value)
+	return e
+}
+
+func (e *zerologEvent) Err(err error) Event {
+	e.event = e.event.Err(err)
+	return e
+}
+
+func (e *zerologEvent) Msg(msg string) {
+	e.event.Msg(msg)
+}
+
+func (e *zerologEvent) Msgf(format string, args ...any) {
+	e.event.Msgf(format, args...)
+}
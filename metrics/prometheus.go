@@ -4,6 +4,7 @@ package metrics
 import (
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -12,49 +13,186 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
+// Options configures optional behavior for NewCollector. The zero value is
+// ready to use and reproduces the collector's previous defaults.
+type Options struct {
+	// DurationBuckets overrides the histogram buckets used for
+	// http_request_duration_seconds and health_check_duration_seconds.
+	// Defaults to prometheus.DefBuckets, which is tuned for typical web
+	// request latencies and is a poor fit for sub-millisecond APIs or slow
+	// batch endpoints. The request/response size histograms use their own
+	// byte-denominated buckets regardless of this setting.
+	DurationBuckets []float64
+	// ConstLabels are attached to every metric this Collector registers,
+	// e.g. prometheus.Labels{"env": "prod", "version": "1.4.0"}.
+	ConstLabels prometheus.Labels
+	// Registerer receives the collector's metrics. Defaults to
+	// prometheus.DefaultRegisterer; pass a fresh prometheus.NewRegistry()
+	// in tests or multi-tenant embeds to avoid "duplicate metrics
+	// collector registration" panics.
+	Registerer prometheus.Registerer
+	// MaxPathCardinality bounds the number of distinct "path" label values
+	// this Collector will track before collapsing further unknown paths
+	// into "other". Zero means unlimited. Guards against unbounded
+	// Prometheus memory growth from 404-scanning traffic hitting routes
+	// chi never matched (so Middleware falls back to r.URL.Path).
+	MaxPathCardinality int
+}
+
 // Collector holds Prometheus metrics for a service.
 type Collector struct {
-	requestsTotal   *prometheus.CounterVec
-	requestDuration *prometheus.HistogramVec
+	requestsTotal    *prometheus.CounterVec
+	requestDuration  *prometheus.HistogramVec
+	requestSize      *prometheus.HistogramVec
+	responseSize     *prometheus.HistogramVec
 	requestsInFlight prometheus.Gauge
 	errorsTotal      *prometheus.CounterVec
+
+	healthCheckStatus   *prometheus.GaugeVec
+	healthCheckDuration *prometheus.HistogramVec
+	healthCheckFailures *prometheus.CounterVec
+
+	cardinalityDropped *prometheus.CounterVec
+
+	maxPathCardinality int
+	pathsMu            sync.Mutex
+	seenPaths          map[string]struct{}
 }
 
-// NewCollector creates a new metrics collector for the given service namespace.
-func NewCollector(namespace string) *Collector {
-	return &Collector{
-		requestsTotal: promauto.NewCounterVec(
+// NewCollector creates a new metrics collector for the given service
+// namespace. opts is optional; without it, NewCollector behaves exactly as
+// before (default buckets, no constant labels, global registerer, unbounded
+// path cardinality).
+func NewCollector(namespace string, opts ...Options) *Collector {
+	var o Options
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	buckets := o.DurationBuckets
+	if buckets == nil {
+		buckets = prometheus.DefBuckets
+	}
+
+	registerer := o.Registerer
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+	factory := promauto.With(registerer)
+
+	c := &Collector{
+		requestsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
-				Namespace: namespace,
-				Name:      "http_requests_total",
-				Help:      "Total number of HTTP requests",
+				Namespace:   namespace,
+				Name:        "http_requests_total",
+				Help:        "Total number of HTTP requests",
+				ConstLabels: o.ConstLabels,
 			},
 			[]string{"method", "path", "status"},
 		),
-		requestDuration: promauto.NewHistogramVec(
+		requestDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   namespace,
+				Name:        "http_request_duration_seconds",
+				Help:        "HTTP request duration in seconds",
+				Buckets:     buckets,
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"method", "path"},
+		),
+		requestSize: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   namespace,
+				Name:        "http_request_size_bytes",
+				Help:        "HTTP request body size in bytes",
+				Buckets:     prometheus.ExponentialBuckets(64, 4, 10),
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"method", "path"},
+		),
+		responseSize: factory.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Namespace: namespace,
-				Name:      "http_request_duration_seconds",
-				Help:      "HTTP request duration in seconds",
-				Buckets:   prometheus.DefBuckets,
+				Namespace:   namespace,
+				Name:        "http_response_size_bytes",
+				Help:        "HTTP response body size in bytes",
+				Buckets:     prometheus.ExponentialBuckets(64, 4, 10),
+				ConstLabels: o.ConstLabels,
 			},
 			[]string{"method", "path"},
 		),
-		requestsInFlight: promauto.NewGauge(
+		requestsInFlight: factory.NewGauge(
 			prometheus.GaugeOpts{
-				Namespace: namespace,
-				Name:      "http_requests_in_flight",
-				Help:      "Current number of HTTP requests being processed",
+				Namespace:   namespace,
+				Name:        "http_requests_in_flight",
+				Help:        "Current number of HTTP requests being processed",
+				ConstLabels: o.ConstLabels,
 			},
 		),
-		errorsTotal: promauto.NewCounterVec(
+		errorsTotal: factory.NewCounterVec(
 			prometheus.CounterOpts{
-				Namespace: namespace,
-				Name:      "errors_total",
-				Help:      "Total number of errors by type",
+				Namespace:   namespace,
+				Name:        "errors_total",
+				Help:        "Total number of errors by type",
+				ConstLabels: o.ConstLabels,
 			},
 			[]string{"type"},
 		),
+		healthCheckStatus: factory.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace:   namespace,
+				Name:        "health_check_status",
+				Help:        "Result of the most recent health check (1 = healthy, 0 = unhealthy)",
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"check"},
+		),
+		healthCheckDuration: factory.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace:   namespace,
+				Name:        "health_check_duration_seconds",
+				Help:        "Health check execution duration in seconds",
+				Buckets:     buckets,
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"check"},
+		),
+		healthCheckFailures: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "health_check_failures_total",
+				Help:        "Total number of failed health check runs",
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"check"},
+		),
+		cardinalityDropped: factory.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace:   namespace,
+				Name:        "metrics_cardinality_dropped_total",
+				Help:        "Total number of requests whose path label was collapsed into \"other\" to bound cardinality",
+				ConstLabels: o.ConstLabels,
+			},
+			[]string{"method"},
+		),
+		maxPathCardinality: o.MaxPathCardinality,
+		seenPaths:          make(map[string]struct{}),
+	}
+
+	return c
+}
+
+// ObserveHealthCheck records the outcome of a single health check run, for
+// use by health.Registry.
+func (c *Collector) ObserveHealthCheck(name string, healthy bool, duration time.Duration) {
+	status := 0.0
+	if healthy {
+		status = 1.0
+	}
+
+	c.healthCheckStatus.WithLabelValues(name).Set(status)
+	c.healthCheckDuration.WithLabelValues(name).Observe(duration.Seconds())
+	if !healthy {
+		c.healthCheckFailures.WithLabelValues(name).Inc()
 	}
 }
 
@@ -82,15 +220,44 @@ func (c *Collector) Middleware(next http.Handler) http.Handler {
 			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
 				path = rctx.RoutePattern()
 			}
+			path = c.boundedPath(r.Method, path)
 
 			c.requestsTotal.WithLabelValues(r.Method, path, strconv.Itoa(ww.statusCode)).Inc()
 			c.requestDuration.WithLabelValues(r.Method, path).Observe(duration)
+
+			if r.ContentLength > 0 {
+				c.requestSize.WithLabelValues(r.Method, path).Observe(float64(r.ContentLength))
+			}
+			c.responseSize.WithLabelValues(r.Method, path).Observe(float64(ww.bytesWritten))
 		}()
 
 		next.ServeHTTP(ww, r)
 	})
 }
 
+// boundedPath returns path, unless MaxPathCardinality is set and path is a
+// new value that would push the number of distinct paths seen so far over
+// that ceiling, in which case it returns "other" and counts the drop.
+func (c *Collector) boundedPath(method, path string) string {
+	if c.maxPathCardinality <= 0 {
+		return path
+	}
+
+	c.pathsMu.Lock()
+	defer c.pathsMu.Unlock()
+
+	if _, ok := c.seenPaths[path]; ok {
+		return path
+	}
+	if len(c.seenPaths) >= c.maxPathCardinality {
+		c.cardinalityDropped.WithLabelValues(method).Inc()
+		return "other"
+	}
+
+	c.seenPaths[path] = struct{}{}
+	return path
+}
+
 // RecordError increments the error counter for the given error type.
 func (c *Collector) RecordError(errorType string) {
 	c.errorsTotal.WithLabelValues(errorType).Inc()
@@ -101,11 +268,13 @@ func Handler() http.Handler {
 	return promhttp.Handler()
 }
 
-// responseWriter wraps http.ResponseWriter to capture the status code.
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// the number of response body bytes written.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
-	written    bool
+	statusCode   int
+	written      bool
+	bytesWritten int64
 }
 
 // WriteHeader captures the status code and delegates to the underlying ResponseWriter.
@@ -117,12 +286,15 @@ func (rw *responseWriter) WriteHeader(code int) {
 	}
 }
 
-// Write ensures WriteHeader is called with 200 OK if not explicitly set.
+// Write ensures WriteHeader is called with 200 OK if not explicitly set, and
+// tallies the bytes written for the response size histogram.
 func (rw *responseWriter) Write(b []byte) (int, error) {
 	if !rw.written {
 		rw.WriteHeader(http.StatusOK)
 	}
-	return rw.ResponseWriter.Write(b)
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytesWritten += int64(n)
+	return n, err
 }
 
 // Unwrap returns the underlying ResponseWriter (for middleware compatibility).
@@ -6,29 +6,101 @@ import (
 	"context"
 	"net/http"
 
+	"go.opentelemetry.io/otel"
+
 	"github.com/Assistencia-Familiar-Francana/go-observability/health"
 	"github.com/Assistencia-Familiar-Francana/go-observability/logging"
 	"github.com/Assistencia-Familiar-Francana/go-observability/metrics"
 	"github.com/Assistencia-Familiar-Francana/go-observability/trace"
-	"github.com/rs/zerolog"
 )
 
 // Stack holds all observability components for a service.
 type Stack struct {
-	logger    *logging.Logger
+	logger    logging.Logger
 	collector *metrics.Collector
+	tracer    *trace.Tracer
+	health    *health.Registry
+	shutdown  func(context.Context) error
+}
+
+// stackConfig accumulates the Options passed to NewStack.
+type stackConfig struct {
+	trace   trace.Config
+	backend logging.LoggerBackend
+}
+
+// Option configures optional behavior on NewStack.
+type Option func(*stackConfig)
+
+// WithOTLPExporter configures the stack to export spans to an OTLP
+// collector at endpoint over the given transport ("grpc" or "http").
+func WithOTLPExporter(kind trace.ExporterKind, endpoint string, insecure bool) Option {
+	return func(c *stackConfig) {
+		c.trace.Exporter = kind
+		c.trace.Endpoint = endpoint
+		c.trace.Insecure = insecure
+	}
+}
+
+// WithSampleRatio sets the fraction (0..1) of traces sampled when there is
+// no parent span to inherit a sampling decision from.
+func WithSampleRatio(ratio float64) Option {
+	return func(c *stackConfig) {
+		c.trace.SampleRatio = ratio
+	}
+}
+
+// WithResourceAttributes sets the service.version and deployment.environment
+// resource attributes attached to every span.
+func WithResourceAttributes(version, environment string) Option {
+	return func(c *stackConfig) {
+		c.trace.ServiceVersion = version
+		c.trace.Environment = environment
+	}
+}
+
+// WithLoggerBackend selects the logging.Logger implementation NewStack
+// builds. Pass logging.BackendZerolog (the default), logging.BackendSlog,
+// logging.BackendSlogPretty, or a custom logging.LoggerBackend factory.
+func WithLoggerBackend(backend logging.LoggerBackend) Option {
+	return func(c *stackConfig) {
+		c.backend = backend
+	}
 }
 
-// NewStack creates a new observability stack for the given service.
-func NewStack(serviceName string, debug bool) *Stack {
+// NewStack creates a new observability stack for the given service. Without
+// any Option, tracing uses a provider that exports nothing and logging uses
+// logging.BackendZerolog, so existing callers keep working unchanged.
+func NewStack(serviceName string, debug bool, opts ...Option) *Stack {
+	cfg := stackConfig{trace: trace.Config{ServiceName: serviceName}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	logger := logging.NewLogger(serviceName, debug, cfg.backend)
+
+	provider, shutdown, err := trace.NewProvider(context.Background(), cfg.trace)
+	if err != nil {
+		// Don't fail service startup over a misconfigured exporter; fall
+		// back to a non-exporting tracer and let the stack's logger surface it.
+		logger.Error().Err(err).Msg("trace exporter setup failed, spans will not be exported")
+		provider, shutdown, _ = trace.NewProvider(context.Background(), trace.Config{})
+	}
+	otel.SetTracerProvider(provider)
+
+	collector := metrics.NewCollector(serviceName)
+
 	return &Stack{
-		logger:    logging.NewLogger(serviceName, debug),
-		collector: metrics.NewCollector(serviceName),
+		logger:    logger,
+		collector: collector,
+		tracer:    trace.NewTracer(provider, serviceName),
+		health:    health.NewRegistry(collector),
+		shutdown:  shutdown,
 	}
 }
 
 // Logger returns the structured logger.
-func (s *Stack) Logger() *logging.Logger {
+func (s *Stack) Logger() logging.Logger {
 	return s.logger
 }
 
@@ -37,9 +109,23 @@ func (s *Stack) Collector() *metrics.Collector {
 	return s.collector
 }
 
-// TraceMiddleware returns middleware for trace context propagation.
-func TraceMiddleware() func(http.Handler) http.Handler {
-	return trace.Middleware
+// Shutdown flushes any buffered spans and releases exporter resources. Call
+// it during graceful shutdown.
+func (s *Stack) Shutdown(ctx context.Context) error {
+	return s.shutdown(ctx)
+}
+
+// Health returns the stack's health check registry, for registering
+// liveness/readiness/startup checks and serving ReadinessHandler/
+// StartupHandler off the same Registry that reports through Collector.
+func (s *Stack) Health() *health.Registry {
+	return s.health
+}
+
+// TraceMiddleware returns middleware that propagates W3C trace context and
+// records a span per request.
+func (s *Stack) TraceMiddleware() func(http.Handler) http.Handler {
+	return s.tracer.Middleware
 }
 
 // MetricsMiddleware returns middleware for metrics collection.
@@ -87,7 +173,14 @@ var (
 	RequestIDFromContext = trace.RequestIDFromContext
 )
 
-// Logger context extractor
-func LoggerFromContext(ctx context.Context) *zerolog.Logger {
+// NewTraceTransport returns an http.RoundTripper that injects the current
+// span's trace context into outbound requests made with it.
+func NewTraceTransport(base http.RoundTripper) http.RoundTripper {
+	return trace.NewTransport(base)
+}
+
+// LoggerFromContext extracts the request-scoped logger set by
+// Stack.LoggingMiddleware from ctx.
+func LoggerFromContext(ctx context.Context) logging.Logger {
 	return logging.FromContext(ctx)
 }
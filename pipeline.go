@@ -0,0 +1,200 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Assistencia-Familiar-Francana/go-observability/trace"
+)
+
+// Middleware names accepted by Pipeline.Only and Pipeline.Without, in the
+// order Pipeline always applies them.
+const (
+	MiddlewareRequestID = "request_id"
+	MiddlewareTrace     = "trace"
+	MiddlewareMetrics   = "metrics"
+	MiddlewareLogging   = "logging"
+	MiddlewareRecoverer = "recoverer"
+)
+
+// pipelineOrder is the order this module requires: trace context must be in
+// place before metrics/logging record it. The recoverer runs last, so it
+// wraps only the innermost handler -- a panic inside the trace, metrics, or
+// logging middleware itself is not caught -- which keeps their recorded
+// status/duration accurate for the handler panics it does catch.
+var pipelineOrder = []string{
+	MiddlewareRequestID,
+	MiddlewareTrace,
+	MiddlewareMetrics,
+	MiddlewareLogging,
+	MiddlewareRecoverer,
+}
+
+// Pipeline composes this module's middlewares in the required order, so
+// callers no longer have to hand-assemble
+// RequestID -> TraceMiddleware -> MetricsMiddleware -> LoggingMiddleware -> Recoverer
+// (and risk silently dropping trace IDs from logs or metrics by getting the
+// order wrong).
+type Pipeline struct {
+	stack     *Stack
+	skip      []string
+	recoverFn func(ctx context.Context, recovered any)
+	enabled   map[string]bool
+}
+
+// PipelineOption configures a Pipeline built by Stack.Pipeline.
+type PipelineOption func(*Pipeline)
+
+// Skip excludes requests whose path starts with any of prefixes from the
+// metrics and logging middlewares (e.g. "/metrics", "/healthz"), while
+// still running request ID assignment, trace propagation, and panic
+// recovery for them.
+func Skip(prefixes ...string) PipelineOption {
+	return func(p *Pipeline) {
+		p.skip = append(p.skip, prefixes...)
+	}
+}
+
+// Recover sets a hook the pipeline's Recoverer stage invokes, in addition to
+// its built-in behavior (logging the panic with the current trace ID and
+// incrementing errors_total{type="panic"}). Use it to forward panics to an
+// external alerting system.
+func Recover(fn func(ctx context.Context, recovered any)) PipelineOption {
+	return func(p *Pipeline) {
+		p.recoverFn = fn
+	}
+}
+
+// Pipeline builds a Pipeline over s's middlewares. Without options, it
+// composes all five stages in pipelineOrder with no path skipped.
+func (s *Stack) Pipeline(opts ...PipelineOption) *Pipeline {
+	p := &Pipeline{
+		stack:   s,
+		enabled: map[string]bool{},
+	}
+	for _, name := range pipelineOrder {
+		p.enabled[name] = true
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Only returns a copy of the pipeline restricted to the named middlewares
+// (still applied in pipelineOrder), for routes that need a subset of the
+// default stack -- e.g. a websocket upgrade route that wants trace
+// propagation but not the duration-reporting metrics wrapper.
+func (p *Pipeline) Only(names ...string) *Pipeline {
+	next := p.clone()
+	for name := range next.enabled {
+		next.enabled[name] = false
+	}
+	for _, name := range names {
+		next.enabled[name] = true
+	}
+	return next
+}
+
+// Without returns a copy of the pipeline with the named middlewares removed.
+func (p *Pipeline) Without(names ...string) *Pipeline {
+	next := p.clone()
+	for _, name := range names {
+		next.enabled[name] = false
+	}
+	return next
+}
+
+func (p *Pipeline) clone() *Pipeline {
+	enabled := make(map[string]bool, len(p.enabled))
+	for name, on := range p.enabled {
+		enabled[name] = on
+	}
+	return &Pipeline{
+		stack:     p.stack,
+		skip:      p.skip,
+		recoverFn: p.recoverFn,
+		enabled:   enabled,
+	}
+}
+
+// Middleware returns the composed middleware, ready to pass to chi's
+// Router.Use or Router.With.
+func (p *Pipeline) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handler := next
+		for i := len(pipelineOrder) - 1; i >= 0; i-- {
+			name := pipelineOrder[i]
+			if !p.enabled[name] {
+				continue
+			}
+			handler = p.stage(name, handler)
+		}
+		return handler
+	}
+}
+
+func (p *Pipeline) stage(name string, next http.Handler) http.Handler {
+	switch name {
+	case MiddlewareRequestID:
+		return trace.RequestIDMiddleware(next)
+	case MiddlewareTrace:
+		return p.stack.tracer.Middleware(next)
+	case MiddlewareMetrics:
+		return p.skipped(p.stack.collector.Middleware, next)
+	case MiddlewareLogging:
+		return p.skipped(p.stack.logger.Middleware, next)
+	case MiddlewareRecoverer:
+		return p.recoverer(next)
+	default:
+		return next
+	}
+}
+
+// skipped wraps mw so that requests whose path starts with a skipped prefix
+// bypass it entirely and go straight to next.
+func (p *Pipeline) skipped(mw func(http.Handler) http.Handler, next http.Handler) http.Handler {
+	if len(p.skip) == 0 {
+		return mw(next)
+	}
+
+	wrapped := mw(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range p.skip {
+			if strings.HasPrefix(r.URL.Path, prefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		wrapped.ServeHTTP(w, r)
+	})
+}
+
+// recoverer catches panics from next, logs them with the request's trace
+// ID, increments errors_total{type="panic"} on the stack's Collector, runs
+// the Recover hook if one was set, and responds 500.
+func (p *Pipeline) recoverer(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+
+			p.stack.logger.WithContext(r.Context()).Error().
+				Str("http.method", r.Method).
+				Str("http.path", r.URL.Path).
+				Msgf("panic recovered: %v", recovered)
+			p.stack.collector.RecordError("panic")
+
+			if p.recoverFn != nil {
+				p.recoverFn(r.Context(), recovered)
+			}
+
+			w.WriteHeader(http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -1,57 +1,240 @@
-// Package trace provides trace context propagation for distributed tracing.
+// Package trace provides W3C trace context propagation and OpenTelemetry-based
+// distributed tracing for HTTP services.
 package trace
 
 import (
 	"context"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 type contextKey string
 
+const requestIDKey contextKey = "request_id"
+
+// ExporterKind selects the OTLP transport used to ship spans to a collector.
+type ExporterKind string
+
 const (
-	traceIDKey   contextKey = "trace_id"
-	requestIDKey contextKey = "request_id"
+	// ExporterNone disables export; NewProvider falls back to a provider
+	// that assigns trace and span IDs but exports nothing.
+	ExporterNone ExporterKind = ""
+	ExporterGRPC ExporterKind = "grpc"
+	ExporterHTTP ExporterKind = "http"
 )
 
-// Middleware returns chi-compatible middleware for trace context propagation.
-// It extracts or generates X-Trace-ID and X-Request-ID headers and adds them to the request context.
-func Middleware(next http.Handler) http.Handler {
+// Config configures the OpenTelemetry TracerProvider built by NewProvider.
+type Config struct {
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+
+	// Exporter selects the OTLP transport. The zero value (ExporterNone)
+	// yields a provider that exports nothing, so services that don't
+	// configure tracing keep working and still get trace IDs.
+	Exporter ExporterKind
+	Endpoint string
+	Insecure bool
+
+	// SampleRatio is the fraction (0..1) of traces sampled when there is no
+	// parent span to inherit a sampling decision from. Defaults to 1.0.
+	SampleRatio float64
+}
+
+// propagator is the W3C traceparent/tracestate propagator used throughout
+// this package for both inbound extraction and outbound injection.
+var propagator = propagation.TraceContext{}
+
+// NewProvider builds an OpenTelemetry TracerProvider and an exporter flush
+// func from cfg. When cfg.Exporter is ExporterNone, it returns a provider
+// with no exporter: spans still get real trace and span IDs (so a request
+// without a traceparent starts a new trace and logs correlate), but nothing
+// is exported.
+func NewProvider(ctx context.Context, cfg Config) (oteltrace.TracerProvider, func(context.Context) error, error) {
+	if cfg.Exporter == ExporterNone {
+		provider := sdktrace.NewTracerProvider()
+		return provider, provider.Shutdown, nil
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(cfg.ServiceVersion),
+		attribute.String("deployment.environment", cfg.Environment),
+	))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio <= 0 {
+		ratio = 1.0
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+
+	return provider, provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	switch cfg.Exporter {
+	case ExporterGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		return otlptrace.New(ctx, otlptracegrpc.NewClient(opts...))
+	case ExporterHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptrace.New(ctx, otlptracehttp.NewClient(opts...))
+	default:
+		return nil, errUnknownExporter(cfg.Exporter)
+	}
+}
+
+type errUnknownExporter ExporterKind
+
+func (e errUnknownExporter) Error() string {
+	return "trace: unknown exporter kind " + string(e)
+}
+
+// Tracer creates spans for HTTP handlers and outbound requests using the
+// OpenTelemetry TracerProvider it was built from.
+type Tracer struct {
+	tracer oteltrace.Tracer
+}
+
+// NewTracer wraps provider into a Tracer scoped to name (typically the
+// service name), ready to use as Stack.TraceMiddleware.
+func NewTracer(provider oteltrace.TracerProvider, name string) *Tracer {
+	return &Tracer{tracer: provider.Tracer(name)}
+}
+
+// Middleware returns chi-compatible middleware that extracts the inbound
+// traceparent/tracestate headers (generating a new trace when absent or
+// malformed), starts a span for the request, and records the standard HTTP
+// span attributes plus error status on completion.
+func (t *Tracer) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Extract or generate trace ID
-		traceID := r.Header.Get("X-Trace-ID")
-		if traceID == "" {
-			traceID = uuid.NewString()
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := t.tracer.Start(ctx, r.Method,
+			oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+			oteltrace.WithAttributes(
+				semconv.HTTPMethod(r.Method),
+				semconv.HTTPTarget(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		ww := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(ww, r.WithContext(ctx))
+
+		// Use the route pattern if chi resolved one, matching the metrics collector.
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			span.SetName(rctx.RoutePattern())
+			span.SetAttributes(semconv.HTTPRoute(rctx.RoutePattern()))
 		}
 
-		// Extract or generate request ID
+		span.SetAttributes(semconv.HTTPStatusCode(ww.statusCode))
+		if ww.statusCode >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(ww.statusCode))
+		}
+	})
+}
+
+// RequestIDMiddleware returns chi-compatible middleware that extracts the
+// inbound X-Request-ID header, generating one when absent, and stores it so
+// RequestIDFromContext can retrieve it downstream.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		requestID := r.Header.Get("X-Request-ID")
 		if requestID == "" {
 			requestID = uuid.NewString()
 		}
+		w.Header().Set("X-Request-ID", requestID)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), requestID)))
+	})
+}
 
-		// Add to context
-		ctx := context.WithValue(r.Context(), traceIDKey, traceID)
-		ctx = context.WithValue(ctx, requestIDKey, requestID)
+// NewTransport wraps base (or http.DefaultTransport when nil) so outbound
+// requests carry the current span's traceparent/tracestate headers, letting
+// downstream services join the same trace.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
 
-		// Add to response headers for client correlation
-		w.Header().Set("X-Trace-ID", traceID)
-		w.Header().Set("X-Request-ID", requestID)
+type transport struct {
+	base http.RoundTripper
+}
 
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+	return t.base.RoundTrip(req)
+}
+
+// StartSpan starts a new span named name using the global OpenTelemetry
+// TracerProvider (the one NewStack registers via otel.SetTracerProvider).
+// Callers must call span.End().
+func StartSpan(ctx context.Context, name string) (context.Context, oteltrace.Span) {
+	return otel.Tracer("go-observability").Start(ctx, name)
+}
+
+// SpanFromContext returns the current span, or a no-op span if none is set.
+func SpanFromContext(ctx context.Context) oteltrace.Span {
+	return oteltrace.SpanFromContext(ctx)
 }
 
-// TraceIDFromContext extracts the trace ID from the context.
+// TraceIDFromContext extracts the active OpenTelemetry trace ID from the
+// context, or "" if there is no valid span.
 func TraceIDFromContext(ctx context.Context) string {
-	if traceID, ok := ctx.Value(traceIDKey).(string); ok {
-		return traceID
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
 	}
-	return ""
+	return sc.TraceID().String()
+}
+
+// SpanIDFromContext extracts the active OpenTelemetry span ID from the
+// context, or "" if there is no valid span.
+func SpanIDFromContext(ctx context.Context) string {
+	sc := oteltrace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return ""
+	}
+	return sc.SpanID().String()
 }
 
-// RequestIDFromContext extracts the request ID from the context.
+// RequestIDFromContext extracts the request ID set by RequestIDMiddleware or
+// WithRequestID from the context.
 func RequestIDFromContext(ctx context.Context) string {
 	if requestID, ok := ctx.Value(requestIDKey).(string); ok {
 		return requestID
@@ -59,12 +242,36 @@ func RequestIDFromContext(ctx context.Context) string {
 	return ""
 }
 
-// WithTraceID returns a new context with the trace ID set.
-func WithTraceID(ctx context.Context, traceID string) context.Context {
-	return context.WithValue(ctx, traceIDKey, traceID)
-}
-
 // WithRequestID returns a new context with the request ID set.
 func WithRequestID(ctx context.Context, requestID string) context.Context {
 	return context.WithValue(ctx, requestIDKey, requestID)
 }
+
+// responseWriter wraps http.ResponseWriter to capture the status code.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	written    bool
+}
+
+// WriteHeader captures the status code and delegates to the underlying ResponseWriter.
+func (rw *responseWriter) WriteHeader(code int) {
+	if !rw.written {
+		rw.statusCode = code
+		rw.written = true
+		rw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write ensures WriteHeader is called with 200 OK if not explicitly set.
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if !rw.written {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter (for middleware compatibility).
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}